@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// labelSelectorFor converts a *metav1.LabelSelector to a labels.Selector,
+// honoring matchExpressions as well as matchLabels.
+func labelSelectorFor(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return nil, fmt.Errorf("target has no pod selector to check readiness against")
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// readyPollInterval is how often podsReady is re-checked while waiting for a
+// deep readiness verification to complete.
+const readyPollInterval = 2 * time.Second
+
+// WaitForDeploymentReady waits, on top of the replica-count check already
+// performed by WaitForDeploymentReplicas, until the deployment's rollout has
+// actually finished and every pod it owns is Running and Ready. This is the
+// same combination of signals Helm's readiness checker uses.
+func WaitForDeploymentReady(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) error {
+	return pollUntilReady(ctx, func() (bool, error) {
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, targetName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if deployment.Status.ObservedGeneration < deployment.Generation {
+			return false, nil
+		}
+		if deployment.Spec.Replicas != nil && deployment.Status.UpdatedReplicas != *deployment.Spec.Replicas {
+			return false, nil
+		}
+		if progressDeadlineExceeded(deployment.Status.Conditions) {
+			return false, fmt.Errorf("deployment %s in %s has exceeded its progress deadline", targetName, namespace)
+		}
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+			return true, nil
+		}
+
+		selector, err := labelSelectorFor(deployment.Spec.Selector)
+		if err != nil {
+			return false, err
+		}
+		return podsReady(ctx, clientset, namespace, selector)
+	})
+}
+
+// WaitForStatefulSetReady waits, on top of the replica-count check already
+// performed by WaitForStatefulSetReplicas, until every pod owned by the
+// statefulset is Running and Ready.
+func WaitForStatefulSetReady(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) error {
+	return pollUntilReady(ctx, func() (bool, error) {
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, targetName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+			return false, nil
+		}
+		if statefulSet.Spec.Replicas != nil && *statefulSet.Spec.Replicas == 0 {
+			return true, nil
+		}
+
+		selector, err := labelSelectorFor(statefulSet.Spec.Selector)
+		if err != nil {
+			return false, err
+		}
+		return podsReady(ctx, clientset, namespace, selector)
+	})
+}
+
+// WaitForReplicaSetReady waits until every pod owned by the replicaset is
+// Running and Ready.
+func WaitForReplicaSetReady(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) error {
+	return pollUntilReady(ctx, func() (bool, error) {
+		replicaSet, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, targetName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if replicaSet.Spec.Replicas != nil && *replicaSet.Spec.Replicas == 0 {
+			return true, nil
+		}
+
+		selector, err := labelSelectorFor(replicaSet.Spec.Selector)
+		if err != nil {
+			return false, err
+		}
+		return podsReady(ctx, clientset, namespace, selector)
+	})
+}
+
+// WaitForRCReady waits until every pod owned by the replicationcontroller is
+// Running and Ready.
+func WaitForRCReady(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) error {
+	return pollUntilReady(ctx, func() (bool, error) {
+		rc, err := clientset.CoreV1().ReplicationControllers(namespace).Get(ctx, targetName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if rc.Spec.Replicas != nil && *rc.Spec.Replicas == 0 {
+			return true, nil
+		}
+		if len(rc.Spec.Selector) == 0 {
+			return false, fmt.Errorf("target has no pod selector to check readiness against")
+		}
+
+		return podsReady(ctx, clientset, namespace, labels.SelectorFromSet(rc.Spec.Selector))
+	})
+}
+
+// WaitForDaemonSetReady waits, on top of the rollout check already performed
+// by WaitForDaemonSetRollout, until every pod owned by the daemonset is
+// Running and Ready.
+func WaitForDaemonSetReady(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) error {
+	return pollUntilReady(ctx, func() (bool, error) {
+		daemonSet, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, targetName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if daemonSet.Status.DesiredNumberScheduled == 0 {
+			return true, nil
+		}
+
+		selector, err := labelSelectorFor(daemonSet.Spec.Selector)
+		if err != nil {
+			return false, err
+		}
+		return podsReady(ctx, clientset, namespace, selector)
+	})
+}
+
+// pollUntilReady re-checks condition every readyPollInterval until it
+// reports ready, returns an error, or ctx is done.
+func pollUntilReady(ctx context.Context, condition func() (bool, error)) error {
+	for {
+		ready, err := condition()
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for pods to become ready: %w", ctx.Err())
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// podsReady lists the pods matching selector and reports whether every one
+// of them is Running with all containers Ready, and none are stuck in a
+// CrashLoopBackOff or ImagePullBackOff waiting state.
+func podsReady(ctx context.Context, clientset *kubernetes.Clientset, namespace string, selector labels.Selector) (bool, error) {
+	if selector.Empty() {
+		return false, fmt.Errorf("target has no pod selector to check readiness against")
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(pods.Items) == 0 {
+		return false, nil
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			return false, nil
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if waiting := cs.State.Waiting; waiting != nil {
+				if waiting.Reason == "CrashLoopBackOff" || waiting.Reason == "ImagePullBackOff" {
+					return false, fmt.Errorf("pod %s/%s container %s is %s: %s", namespace, pod.Name, cs.Name, waiting.Reason, waiting.Message)
+				}
+			}
+			if !cs.Ready {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func progressDeadlineExceeded(conditions []v1.DeploymentCondition) bool {
+	for _, c := range conditions {
+		if c.Type == v1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
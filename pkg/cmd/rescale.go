@@ -3,21 +3,36 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 
 	v1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd/api"
+	watchtools "k8s.io/client-go/tools/watch"
 )
 
+// restartedAtAnnotation mirrors the annotation `kubectl rollout restart` uses
+// to force a new rollout without changing the replica count.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// defaultTimeout is used when neither --timeout nor the deprecated
+// --max-wait-seconds is supplied.
+const defaultTimeout = 5 * time.Minute
+
 var commandUsageExample = `
 	# scale a deployment to 0 replicas then back up to the original count
 	kubectl rescale deployment/nginx
@@ -25,8 +40,8 @@ var commandUsageExample = `
 	# scale a statefulset to 0 replicas then back up to the original count
 	kubectl rescale statefulset/mysql
 
-	# scale a statefulset to 0 replicas then back up to the original count, and wait for a maximum of 600 seconds to do so
-	kubectl rescale statefulset/mysql --max-wait-seconds=600
+	# scale a statefulset to 0 replicas then back up to the original count, and wait for a maximum of 10 minutes to do so
+	kubectl rescale statefulset/mysql --timeout=10m
 
 	# it also supports short names
 	kubectl rescale sts/mysql
@@ -36,6 +51,26 @@ var commandUsageExample = `
 
 	# a namespace can also be supplied
 	kubectl rescale deployment/nginx -n dev
+
+	# replicasets, replicationcontrollers, and daemonsets are supported too
+	kubectl rescale replicaset/nginx
+	kubectl rescale rc/nginx
+	kubectl rescale daemonset/fluentd
+
+	# force a rolling restart (rollout restart style) instead of a scale-to-zero-and-back
+	kubectl rescale deploy/nginx --restart
+
+	# rescale several named targets at once
+	kubectl rescale deploy/a deploy/b sts/c
+
+	# rescale every deployment and statefulset matching a label selector, 8 at a time
+	kubectl rescale --selector app=myapp --kind=deployment,statefulset --parallelism=8
+
+	# abort if the deployment isn't at the replica count / resource version you last observed
+	kubectl rescale deploy/nginx --current-replicas=3 --resource-version=12345
+
+	# wait for the scaled-back pods to actually be Running and Ready, not just for the replica count to match
+	kubectl rescale deploy/nginx --wait=ready
 `
 
 var errNoContext = fmt.Errorf("no or invalid context is set, use %q to select a new one", "kubectl config use-context <context>")
@@ -55,7 +90,32 @@ type RescaleOptions struct {
 	targetName string
 	targetKind string
 
-	maxWaitSeconds int
+	targets     []targetRef
+	selector    string
+	kindFilter  string
+	parallelism int
+
+	timeout time.Duration
+	restart bool
+
+	currentReplicas int32
+	resourceVersion string
+
+	waitMode string
+}
+
+// ScalePrecondition mirrors the `kubectl scale` preconditions: when set, the
+// observed Scale must match before the first UpdateScale call is issued.
+type ScalePrecondition struct {
+	CurrentReplicas int32
+	ResourceVersion string
+}
+
+// targetRef identifies a single object to rescale, as parsed from a
+// `<kind>/<name>` positional argument.
+type targetRef struct {
+	kind string
+	name string
 }
 
 // NewRescaleOptions provides an instance of RescaleOptions with default values
@@ -71,15 +131,15 @@ func NewCmdRescale(streams genericclioptions.IOStreams) *cobra.Command {
 	o := NewRescaleOptions(streams)
 
 	cmd := &cobra.Command{
-		Use:          "rescale [name of deployment/statefulset] [flags]",
-		Short:        "Scale a deployment or statefulset to 0 then back up",
+		Use:          "rescale [name of deployment/statefulset/replicaset/replicationcontroller/daemonset] [flags]",
+		Short:        "Scale or restart a workload then return it to its original state",
 		Example:      commandUsageExample,
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.Complete(c, args); err != nil {
 				return err
 			}
-			if err := o.Run(); err != nil {
+			if err := o.Run(c); err != nil {
 				return err
 			}
 
@@ -87,7 +147,16 @@ func NewCmdRescale(streams genericclioptions.IOStreams) *cobra.Command {
 		},
 	}
 
-	cmd.PersistentFlags().IntP("max-wait-seconds", "w", 300, "max number of seconds to wait for the scaled objects to reach desired number of replicas [default: 300]")
+	cmd.PersistentFlags().Duration("timeout", defaultTimeout, "max time to wait for the scaled objects to reach their desired state, e.g. 30s, 10m [default: 5m]")
+	cmd.PersistentFlags().IntP("max-wait-seconds", "w", 0, "deprecated: use --timeout instead")
+	cmd.PersistentFlags().MarkDeprecated("max-wait-seconds", "use --timeout instead")
+	cmd.PersistentFlags().BoolVar(&o.restart, "restart", false, "force a rollout-restart style rescale of deployments and statefulsets (patch the pod template instead of scaling to 0 and back); daemonsets are always restarted this way, replicasets and replicationcontrollers are always scaled to 0 and back")
+	cmd.PersistentFlags().StringVarP(&o.selector, "selector", "l", "", "label selector to rescale every matching object instead of naming one on the command line")
+	cmd.PersistentFlags().StringVar(&o.kindFilter, "kind", "deployment,statefulset", "comma-separated list of kinds to match when --selector is used")
+	cmd.PersistentFlags().IntVar(&o.parallelism, "parallelism", 4, "number of targets to rescale concurrently in batch mode")
+	cmd.PersistentFlags().Int32Var(&o.currentReplicas, "current-replicas", -1, "precondition for the current number of replicas; -1 means no check is performed")
+	cmd.PersistentFlags().StringVar(&o.resourceVersion, "resource-version", "", "precondition for the object's resource version; empty means no check is performed")
+	cmd.PersistentFlags().StringVar(&o.waitMode, "wait", "replicas", "how to confirm the rescale succeeded: \"ready\" (wait for pods to be Running and Ready), \"replicas\" (wait for the replica count to match, the historical behaviour), or \"none\" (don't wait at all)")
 
 	o.configFlags.AddFlags(cmd.Flags())
 
@@ -105,7 +174,7 @@ func (o *RescaleOptions) Complete(cmd *cobra.Command, args []string) error {
 
 	o.restConfig, err = o.configFlags.ToRESTConfig()
 	if err != nil {
-		panic(err.Error())
+		return err
 	}
 
 	o.userSpecifiedContext, err = cmd.Flags().GetString("context")
@@ -120,32 +189,47 @@ func (o *RescaleOptions) Complete(cmd *cobra.Command, args []string) error {
 		return errNoContext
 	}
 
-	o.maxWaitSeconds, err = cmd.Flags().GetInt("max-wait-seconds")
+	o.timeout, err = cmd.Flags().GetDuration("timeout")
 	if err != nil {
 		return err
 	}
-	if o.maxWaitSeconds <= 0 {
-		return fmt.Errorf("invalid max number of waiting seconds provided")
+	if cmd.Flags().Changed("max-wait-seconds") {
+		maxWaitSeconds, err := cmd.Flags().GetInt("max-wait-seconds")
+		if err != nil {
+			return err
+		}
+		o.timeout = time.Duration(maxWaitSeconds) * time.Second
 	}
-
-	if len(args) != 1 {
-		return fmt.Errorf("either a deployment or a statefulset must be provided")
+	if o.timeout <= 0 {
+		return fmt.Errorf("invalid timeout provided")
 	}
-	if strings.HasPrefix(args[0], "deployment/") {
-		o.targetName = strings.TrimPrefix(args[0], "deployment/")
-		o.targetKind = "deployment"
-	} else if strings.HasPrefix(args[0], "deploy/") {
-		o.targetName = strings.TrimPrefix(args[0], "deploy/")
-		o.targetKind = "deployment"
-	} else if strings.HasPrefix(args[0], "statefulset/") {
-		o.targetName = strings.TrimPrefix(args[0], "statefulset/")
-		o.targetKind = "statefulset"
-	} else if strings.HasPrefix(args[0], "sts/") {
-		o.targetName = strings.TrimPrefix(args[0], "sts/")
-		o.targetKind = "statefulset"
+
+	if len(o.selector) > 0 {
+		if len(args) > 0 {
+			return fmt.Errorf("positional targets and --selector are mutually exclusive")
+		}
 	} else {
-		o.targetName = args[0]
-		o.targetKind = "unknown"
+		if len(args) == 0 {
+			return fmt.Errorf("either a deployment, statefulset, replicaset, replicationcontroller, or daemonset must be provided, or --selector must be used")
+		}
+		o.targets = make([]targetRef, 0, len(args))
+		for _, arg := range args {
+			o.targets = append(o.targets, parseTargetArg(arg))
+		}
+		if len(o.targets) == 1 {
+			o.targetName = o.targets[0].name
+			o.targetKind = o.targets[0].kind
+		}
+	}
+
+	if o.parallelism <= 0 {
+		return fmt.Errorf("invalid parallelism provided")
+	}
+
+	switch o.waitMode {
+	case "ready", "replicas", "none":
+	default:
+		return fmt.Errorf("invalid --wait mode %q (must be one of: ready, replicas, none)", o.waitMode)
 	}
 
 	o.userSpecifiedNamespace, err = cmd.Flags().GetString("namespace")
@@ -156,33 +240,79 @@ func (o *RescaleOptions) Complete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// scalePrecondition builds a ScalePrecondition from the --current-replicas
+// and --resource-version flags, or nil if neither was set.
+func (o *RescaleOptions) scalePrecondition() *ScalePrecondition {
+	if o.currentReplicas < 0 && len(o.resourceVersion) == 0 {
+		return nil
+	}
+	return &ScalePrecondition{CurrentReplicas: o.currentReplicas, ResourceVersion: o.resourceVersion}
+}
+
+// parseTargetArg splits a `<kind>/<name>` positional argument into a
+// targetRef, accepting both the long and short forms of each kind. A bare
+// name (no prefix) is returned with kind "unknown" so Run can resolve it.
+func parseTargetArg(arg string) targetRef {
+	switch {
+	case strings.HasPrefix(arg, "deployment/"):
+		return targetRef{kind: "deployment", name: strings.TrimPrefix(arg, "deployment/")}
+	case strings.HasPrefix(arg, "deploy/"):
+		return targetRef{kind: "deployment", name: strings.TrimPrefix(arg, "deploy/")}
+	case strings.HasPrefix(arg, "statefulset/"):
+		return targetRef{kind: "statefulset", name: strings.TrimPrefix(arg, "statefulset/")}
+	case strings.HasPrefix(arg, "sts/"):
+		return targetRef{kind: "statefulset", name: strings.TrimPrefix(arg, "sts/")}
+	case strings.HasPrefix(arg, "replicaset/"):
+		return targetRef{kind: "replicaset", name: strings.TrimPrefix(arg, "replicaset/")}
+	case strings.HasPrefix(arg, "rs/"):
+		return targetRef{kind: "replicaset", name: strings.TrimPrefix(arg, "rs/")}
+	case strings.HasPrefix(arg, "replicationcontroller/"):
+		return targetRef{kind: "replicationcontroller", name: strings.TrimPrefix(arg, "replicationcontroller/")}
+	case strings.HasPrefix(arg, "rc/"):
+		return targetRef{kind: "replicationcontroller", name: strings.TrimPrefix(arg, "rc/")}
+	case strings.HasPrefix(arg, "daemonset/"):
+		return targetRef{kind: "daemonset", name: strings.TrimPrefix(arg, "daemonset/")}
+	case strings.HasPrefix(arg, "ds/"):
+		return targetRef{kind: "daemonset", name: strings.TrimPrefix(arg, "ds/")}
+	default:
+		return targetRef{kind: "unknown", name: arg}
+	}
+}
+
 // Run lists all available namespaces on a user's KUBECONFIG or updates the
 // current context based on a provided namespace.
-func (o *RescaleOptions) Run() error {
+func (o *RescaleOptions) Run(cmd *cobra.Command) error {
 	clientset, err := kubernetes.NewForConfig(o.restConfig)
 	if err != nil {
-		panic(err.Error())
+		return err
 	}
 
-	var ctx string
+	ctx, cancel := context.WithTimeout(cmd.Context(), o.timeout)
+	defer cancel()
+
+	var kubeContext string
 	if len(o.userSpecifiedContext) > 0 {
-		ctx = o.userSpecifiedContext
+		kubeContext = o.userSpecifiedContext
 	} else {
-		ctx = o.rawConfig.CurrentContext
+		kubeContext = o.rawConfig.CurrentContext
 	}
 
 	var namespace string
 	if len(o.userSpecifiedNamespace) > 0 {
 		namespace = o.userSpecifiedNamespace
 	} else {
-		namespace = o.rawConfig.Contexts[ctx].Namespace
+		namespace = o.rawConfig.Contexts[kubeContext].Namespace
+	}
+
+	if len(o.selector) > 0 || len(o.targets) > 1 {
+		return o.RunBatch(ctx, clientset, namespace)
 	}
 
 	if o.targetKind == "unknown" {
-		_, err = GetDeployment(clientset, namespace, o.targetName)
+		_, err = GetDeployment(ctx, clientset, namespace, o.targetName)
 		if err != nil {
 			if errors.IsNotFound(err) {
-				_, err = GetStatefulSet(clientset, namespace, o.targetName)
+				_, err = GetStatefulSet(ctx, clientset, namespace, o.targetName)
 				if errors.IsNotFound(err) {
 					notFoundError := fmt.Errorf("deployment/statefulset %s cannot be found", o.targetName)
 					fmt.Println(notFoundError.Error())
@@ -200,27 +330,413 @@ func (o *RescaleOptions) Run() error {
 		}
 	}
 
-	if o.targetKind == "deployment" {
-		err = ScaleDeployment(clientset, namespace, o.targetName, o.maxWaitSeconds)
-		if err != nil {
-			return err
+	switch o.targetKind {
+	case "deployment":
+		if o.restart {
+			err = RestartDeployment(ctx, clientset, namespace, o.targetName, o.waitMode)
+		} else {
+			err = ScaleDeployment(ctx, clientset, namespace, o.targetName, o.scalePrecondition(), o.waitMode)
 		}
-	} else if o.targetKind == "statefulset" {
-		err = ScaleStatefulSet(clientset, namespace, o.targetName, o.maxWaitSeconds)
-		if err != nil {
-			return err
+	case "statefulset":
+		if o.restart {
+			err = RestartStatefulSet(ctx, clientset, namespace, o.targetName, o.waitMode)
+		} else {
+			err = ScaleStatefulSet(ctx, clientset, namespace, o.targetName, o.scalePrecondition(), o.waitMode)
 		}
-	} else {
+	case "replicaset":
+		err = ScaleReplicaSet(ctx, clientset, namespace, o.targetName, o.scalePrecondition(), o.waitMode)
+	case "replicationcontroller":
+		err = ScaleReplicationController(ctx, clientset, namespace, o.targetName, o.scalePrecondition(), o.waitMode)
+	case "daemonset":
+		err = RestartDaemonSet(ctx, clientset, namespace, o.targetName, o.waitMode)
+	default:
 		notFoundError := fmt.Errorf("unknown target kind %s", o.targetKind)
 		fmt.Println(notFoundError.Error())
 		return notFoundError
 	}
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func ScaleDeployment(clientset *kubernetes.Clientset, namespace string, targetName string, maxWaitSeconds int) error {
-	deployment, err := GetDeployment(clientset, namespace, targetName)
+// BatchResult records the outcome of rescaling a single target in batch mode.
+type BatchResult struct {
+	Name             string
+	Kind             string
+	OriginalReplicas int32
+	FinalReplicas    int32
+	Duration         time.Duration
+	Err              error
+}
+
+// RunBatch resolves the batch's targets (either the positional args already
+// parsed into o.targets, or every object matching o.selector/o.kindFilter),
+// rescales them concurrently, and prints a summary table.
+func (o *RescaleOptions) RunBatch(ctx context.Context, clientset *kubernetes.Clientset, namespace string) error {
+	targets := o.targets
+	if len(o.selector) > 0 {
+		resolved, err := resolveSelectorTargets(ctx, clientset, namespace, o.selector, o.kindFilter)
+		if err != nil {
+			return err
+		}
+		targets = resolved
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no targets matched")
+	}
+
+	results := RescaleBatch(ctx, clientset, namespace, targets, o.parallelism, o.restart, o.waitMode, o.scalePrecondition())
+	printBatchResults(o.Out, results)
+
+	if failed := countBatchErrors(results); failed > 0 {
+		return fmt.Errorf("%d of %d targets failed to rescale", failed, len(results))
+	}
+
+	return nil
+}
+
+// resolveSelectorTargets lists every object of each kind in kindFilter
+// (comma-separated) matching selector within namespace.
+func resolveSelectorTargets(ctx context.Context, clientset *kubernetes.Clientset, namespace string, selector string, kindFilter string) ([]targetRef, error) {
+	var targets []targetRef
+
+	for _, kind := range strings.Split(kindFilter, ",") {
+		kind = strings.TrimSpace(strings.ToLower(kind))
+		switch kind {
+		case "deployment", "deploy":
+			list, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range list.Items {
+				targets = append(targets, targetRef{kind: "deployment", name: d.Name})
+			}
+		case "statefulset", "sts":
+			list, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return nil, err
+			}
+			for _, s := range list.Items {
+				targets = append(targets, targetRef{kind: "statefulset", name: s.Name})
+			}
+		default:
+			return nil, fmt.Errorf("unsupported --kind %q for selector-based rescale (supported: deployment, statefulset)", kind)
+		}
+	}
+
+	return targets, nil
+}
+
+// RescaleBatch rescales every target in parallel (bounded by parallelism)
+// honoring restart and waitMode the same way the single-target path does.
+// DaemonSets have no scale subresource, so they always go through the
+// restart path regardless of restart, matching Run's single-target dispatch.
+// Restart targets are patched with a new restartedAt annotation and waited on
+// independently (precondition does not apply, matching the single-target
+// restart path). Every other target's scale-to-zero is checked against
+// precondition (when non-nil) before scaling, scaled to 0 in parallel, waited
+// on, then scaled back to its individually recorded original replica count in
+// parallel; a target that fails to reach 0 is excluded from the scale-back
+// phase.
+func RescaleBatch(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targets []targetRef, parallelism int, restart bool, waitMode string, precondition *ScalePrecondition) []BatchResult {
+	results := make([]BatchResult, len(targets))
+	started := make([]time.Time, len(targets))
+	for i, t := range targets {
+		results[i].Name = t.name
+		results[i].Kind = t.kind
+		started[i] = time.Now()
+	}
+
+	var restartIdx, scaleIdx []int
+	for i, t := range targets {
+		if t.kind == "daemonset" || (restart && (t.kind == "deployment" || t.kind == "statefulset")) {
+			restartIdx = append(restartIdx, i)
+		} else {
+			scaleIdx = append(scaleIdx, i)
+		}
+	}
+
+	var g errgroup.Group
+	g.SetLimit(parallelism)
+	for _, i := range restartIdx {
+		i, t := i, targets[i]
+		g.Go(func() error {
+			replicas, err := scaleTargetRestart(ctx, clientset, namespace, t, waitMode)
+			results[i].OriginalReplicas = replicas
+			results[i].FinalReplicas = replicas
+			results[i].Duration = time.Since(started[i])
+			results[i].Err = err
+			return nil
+		})
+	}
+	g.Wait()
+
+	if len(scaleIdx) == 0 {
+		return results
+	}
+
+	var down errgroup.Group
+	down.SetLimit(parallelism)
+	for _, i := range scaleIdx {
+		i, t := i, targets[i]
+		down.Go(func() error {
+			original, err := scaleTargetToZero(ctx, clientset, namespace, t, waitMode, precondition)
+			results[i].OriginalReplicas = original
+			results[i].Err = err
+			return nil
+		})
+	}
+	down.Wait()
+
+	var up errgroup.Group
+	up.SetLimit(parallelism)
+	for _, i := range scaleIdx {
+		i, t := i, targets[i]
+		if results[i].Err != nil {
+			results[i].Duration = time.Since(started[i])
+			continue
+		}
+		up.Go(func() error {
+			final, err := scaleTargetBack(ctx, clientset, namespace, t, results[i].OriginalReplicas, waitMode)
+			results[i].FinalReplicas = final
+			results[i].Duration = time.Since(started[i])
+			results[i].Err = err
+			return nil
+		})
+	}
+	up.Wait()
+
+	return results
+}
+
+func scaleTargetToZero(ctx context.Context, clientset *kubernetes.Clientset, namespace string, t targetRef, waitMode string, precondition *ScalePrecondition) (int32, error) {
+	switch t.kind {
+	case "deployment":
+		deployment, err := GetDeployment(ctx, clientset, namespace, t.name)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := UpdateDeploymentScale(ctx, clientset, namespace, t.name, 0, precondition); err != nil {
+			return deployment.Status.Replicas, err
+		}
+		if waitMode != "none" {
+			if err := WaitForDeploymentReplicas(ctx, clientset, namespace, t.name, 0); err != nil {
+				return deployment.Status.Replicas, err
+			}
+		}
+		return deployment.Status.Replicas, nil
+	case "statefulset":
+		statefulSet, err := GetStatefulSet(ctx, clientset, namespace, t.name)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := UpdateStatefulSetScale(ctx, clientset, namespace, t.name, 0, precondition); err != nil {
+			return statefulSet.Status.Replicas, err
+		}
+		if waitMode != "none" {
+			if err := WaitForStatefulSetReplicas(ctx, clientset, namespace, t.name, 0); err != nil {
+				return statefulSet.Status.Replicas, err
+			}
+		}
+		return statefulSet.Status.Replicas, nil
+	case "replicaset":
+		replicaSet, err := GetReplicaSet(ctx, clientset, namespace, t.name)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := UpdateReplicaSetScale(ctx, clientset, namespace, t.name, 0, precondition); err != nil {
+			return replicaSet.Status.Replicas, err
+		}
+		if waitMode != "none" {
+			if err := WaitForReplicaSetReplicas(ctx, clientset, namespace, t.name, 0); err != nil {
+				return replicaSet.Status.Replicas, err
+			}
+		}
+		return replicaSet.Status.Replicas, nil
+	case "replicationcontroller":
+		rc, err := GetReplicationController(ctx, clientset, namespace, t.name)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := UpdateRCScale(ctx, clientset, namespace, t.name, 0, precondition); err != nil {
+			return rc.Status.Replicas, err
+		}
+		if waitMode != "none" {
+			if err := WaitForRCReplicas(ctx, clientset, namespace, t.name, 0); err != nil {
+				return rc.Status.Replicas, err
+			}
+		}
+		return rc.Status.Replicas, nil
+	default:
+		return 0, fmt.Errorf("batch rescale does not support kind %q", t.kind)
+	}
+}
+
+func scaleTargetBack(ctx context.Context, clientset *kubernetes.Clientset, namespace string, t targetRef, originalReplicas int32, waitMode string) (int32, error) {
+	switch t.kind {
+	case "deployment":
+		if _, err := UpdateDeploymentScale(ctx, clientset, namespace, t.name, originalReplicas, nil); err != nil {
+			return 0, err
+		}
+		if waitMode != "none" {
+			if err := WaitForDeploymentReplicas(ctx, clientset, namespace, t.name, originalReplicas); err != nil {
+				return 0, err
+			}
+			if waitMode == "ready" {
+				if err := WaitForDeploymentReady(ctx, clientset, namespace, t.name); err != nil {
+					return 0, err
+				}
+			}
+		}
+		return originalReplicas, nil
+	case "statefulset":
+		if _, err := UpdateStatefulSetScale(ctx, clientset, namespace, t.name, originalReplicas, nil); err != nil {
+			return 0, err
+		}
+		if waitMode != "none" {
+			if err := WaitForStatefulSetReplicas(ctx, clientset, namespace, t.name, originalReplicas); err != nil {
+				return 0, err
+			}
+			if waitMode == "ready" {
+				if err := WaitForStatefulSetReady(ctx, clientset, namespace, t.name); err != nil {
+					return 0, err
+				}
+			}
+		}
+		return originalReplicas, nil
+	case "replicaset":
+		if _, err := UpdateReplicaSetScale(ctx, clientset, namespace, t.name, originalReplicas, nil); err != nil {
+			return 0, err
+		}
+		if waitMode != "none" {
+			if err := WaitForReplicaSetReplicas(ctx, clientset, namespace, t.name, originalReplicas); err != nil {
+				return 0, err
+			}
+			if waitMode == "ready" {
+				if err := WaitForReplicaSetReady(ctx, clientset, namespace, t.name); err != nil {
+					return 0, err
+				}
+			}
+		}
+		return originalReplicas, nil
+	case "replicationcontroller":
+		if _, err := UpdateRCScale(ctx, clientset, namespace, t.name, originalReplicas, nil); err != nil {
+			return 0, err
+		}
+		if waitMode != "none" {
+			if err := WaitForRCReplicas(ctx, clientset, namespace, t.name, originalReplicas); err != nil {
+				return 0, err
+			}
+			if waitMode == "ready" {
+				if err := WaitForRCReady(ctx, clientset, namespace, t.name); err != nil {
+					return 0, err
+				}
+			}
+		}
+		return originalReplicas, nil
+	default:
+		return 0, fmt.Errorf("batch rescale does not support kind %q", t.kind)
+	}
+}
+
+// scaleTargetRestart patches the target's restartedAt annotation (the same
+// rolling-restart path RestartDeployment/RestartStatefulSet use) instead of
+// scaling to 0 and back, and waits on it per waitMode. The replica count is
+// unaffected by a restart, so the same value is returned as both the
+// "original" and "final" replica count for the results table.
+func scaleTargetRestart(ctx context.Context, clientset *kubernetes.Clientset, namespace string, t targetRef, waitMode string) (int32, error) {
+	switch t.kind {
+	case "deployment":
+		deployment, err := GetDeployment(ctx, clientset, namespace, t.name)
+		if err != nil {
+			return 0, err
+		}
+		if err := patchRestartedAt(ctx, clientset, namespace, t.name, "deployments"); err != nil {
+			return deployment.Status.Replicas, err
+		}
+		if waitMode != "none" {
+			if err := WaitForDeploymentReplicas(ctx, clientset, namespace, t.name, deployment.Status.Replicas); err != nil {
+				return deployment.Status.Replicas, err
+			}
+			if waitMode == "ready" {
+				if err := WaitForDeploymentReady(ctx, clientset, namespace, t.name); err != nil {
+					return deployment.Status.Replicas, err
+				}
+			}
+		}
+		return deployment.Status.Replicas, nil
+	case "statefulset":
+		statefulSet, err := GetStatefulSet(ctx, clientset, namespace, t.name)
+		if err != nil {
+			return 0, err
+		}
+		if err := patchRestartedAt(ctx, clientset, namespace, t.name, "statefulsets"); err != nil {
+			return statefulSet.Status.Replicas, err
+		}
+		if waitMode != "none" {
+			if err := WaitForStatefulSetReplicas(ctx, clientset, namespace, t.name, statefulSet.Status.Replicas); err != nil {
+				return statefulSet.Status.Replicas, err
+			}
+			if waitMode == "ready" {
+				if err := WaitForStatefulSetReady(ctx, clientset, namespace, t.name); err != nil {
+					return statefulSet.Status.Replicas, err
+				}
+			}
+		}
+		return statefulSet.Status.Replicas, nil
+	case "daemonset":
+		daemonSet, err := GetDaemonSet(ctx, clientset, namespace, t.name)
+		if err != nil {
+			return 0, err
+		}
+		if err := patchRestartedAt(ctx, clientset, namespace, t.name, "daemonsets"); err != nil {
+			return daemonSet.Status.DesiredNumberScheduled, err
+		}
+		if waitMode != "none" {
+			if err := WaitForDaemonSetRollout(ctx, clientset, namespace, t.name); err != nil {
+				return daemonSet.Status.DesiredNumberScheduled, err
+			}
+			if waitMode == "ready" {
+				if err := WaitForDaemonSetReady(ctx, clientset, namespace, t.name); err != nil {
+					return daemonSet.Status.DesiredNumberScheduled, err
+				}
+			}
+		}
+		return daemonSet.Status.DesiredNumberScheduled, nil
+	default:
+		return 0, fmt.Errorf("batch restart does not support kind %q", t.kind)
+	}
+}
+
+func countBatchErrors(results []BatchResult) int {
+	count := 0
+	for _, r := range results {
+		if r.Err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// printBatchResults writes a per-target status table: Name, Kind,
+// OriginalReplicas, FinalReplicas, Duration, and Error (if any).
+func printBatchResults(out io.Writer, results []BatchResult) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tKIND\tORIGINAL\tFINAL\tDURATION\tERROR")
+	for _, r := range results {
+		errStr := ""
+		if r.Err != nil {
+			errStr = r.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n", r.Name, r.Kind, r.OriginalReplicas, r.FinalReplicas, r.Duration.Round(time.Second), errStr)
+	}
+	w.Flush()
+}
+
+func ScaleDeployment(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, precondition *ScalePrecondition, waitMode string) error {
+	deployment, err := GetDeployment(ctx, clientset, namespace, targetName)
 	if err != nil {
 		return err
 	}
@@ -228,33 +744,38 @@ func ScaleDeployment(clientset *kubernetes.Clientset, namespace string, targetNa
 	var originalReplicas = deployment.Status.Replicas
 	fmt.Printf("Deployment %s in %s has %d replicas. Scaling to 0...\n", deployment.Name, namespace, originalReplicas)
 
-	_, err = UpdateDeploymentScale(clientset, namespace, targetName, 0)
-	if err != nil {
-		panic(err.Error())
+	if _, err := UpdateDeploymentScale(ctx, clientset, namespace, targetName, 0, precondition); err != nil {
+		return err
 	}
 
-	err = WaitForDeploymentReplicas(clientset, namespace, targetName, 0, maxWaitSeconds)
-	if err != nil {
-		panic(err.Error())
+	if waitMode != "none" {
+		if err := WaitForDeploymentReplicas(ctx, clientset, namespace, targetName, 0); err != nil {
+			return err
+		}
 	}
 	fmt.Printf("Deployment %s in %s now has 0 replicas. Scaling back to %d...\n", deployment.Name, namespace, originalReplicas)
 
-	_, err = UpdateDeploymentScale(clientset, namespace, targetName, originalReplicas)
-	if err != nil {
-		panic(err.Error())
+	if _, err := UpdateDeploymentScale(ctx, clientset, namespace, targetName, originalReplicas, nil); err != nil {
+		return err
 	}
 
-	err = WaitForDeploymentReplicas(clientset, namespace, targetName, originalReplicas, 60)
-	if err != nil {
-		panic(err.Error())
+	if waitMode != "none" {
+		if err := WaitForDeploymentReplicas(ctx, clientset, namespace, targetName, originalReplicas); err != nil {
+			return err
+		}
+		if waitMode == "ready" {
+			if err := WaitForDeploymentReady(ctx, clientset, namespace, targetName); err != nil {
+				return err
+			}
+		}
 	}
 	fmt.Printf("Deployment %s in %s has now been scaled back to %d\n", deployment.Name, namespace, originalReplicas)
 
 	return nil
 }
 
-func ScaleStatefulSet(clientset *kubernetes.Clientset, namespace string, targetName string, maxWaitSeconds int) error {
-	statefulSet, err := GetStatefulSet(clientset, namespace, targetName)
+func ScaleStatefulSet(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, precondition *ScalePrecondition, waitMode string) error {
+	statefulSet, err := GetStatefulSet(ctx, clientset, namespace, targetName)
 	if err != nil {
 		return err
 	}
@@ -262,103 +783,548 @@ func ScaleStatefulSet(clientset *kubernetes.Clientset, namespace string, targetN
 	var originalReplicas = statefulSet.Status.Replicas
 	fmt.Printf("StatefulSet %s in %s has %d replicas. Scaling to 0...\n", statefulSet.Name, namespace, originalReplicas)
 
-	_, err = UpdateStatefulSetScale(clientset, namespace, targetName, 0)
-	if err != nil {
-		panic(err.Error())
+	if _, err := UpdateStatefulSetScale(ctx, clientset, namespace, targetName, 0, precondition); err != nil {
+		return err
 	}
 
-	err = WaitForStatefulSetReplicas(clientset, namespace, targetName, 0, maxWaitSeconds)
-	if err != nil {
-		panic(err.Error())
+	if waitMode != "none" {
+		if err := WaitForStatefulSetReplicas(ctx, clientset, namespace, targetName, 0); err != nil {
+			return err
+		}
 	}
 	fmt.Printf("StatefulSet %s in %s now has 0 replicas. Scaling back to %d...\n", statefulSet.Name, namespace, originalReplicas)
 
-	_, err = UpdateStatefulSetScale(clientset, namespace, targetName, originalReplicas)
-	if err != nil {
-		panic(err.Error())
+	if _, err := UpdateStatefulSetScale(ctx, clientset, namespace, targetName, originalReplicas, nil); err != nil {
+		return err
 	}
 
-	err = WaitForStatefulSetReplicas(clientset, namespace, targetName, originalReplicas, 60)
-	if err != nil {
-		panic(err.Error())
+	if waitMode != "none" {
+		if err := WaitForStatefulSetReplicas(ctx, clientset, namespace, targetName, originalReplicas); err != nil {
+			return err
+		}
+		if waitMode == "ready" {
+			if err := WaitForStatefulSetReady(ctx, clientset, namespace, targetName); err != nil {
+				return err
+			}
+		}
 	}
 	fmt.Printf("StatefulSet %s in %s has now been scaled back to %d\n", statefulSet.Name, namespace, originalReplicas)
 
 	return nil
 }
 
-func GetDeployment(clientset *kubernetes.Clientset, namespace string, targetName string) (*v1.Deployment, error) {
-	deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+func GetDeployment(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) (*v1.Deployment, error) {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, targetName, metav1.GetOptions{})
 	if errors.IsNotFound(err) {
 		return nil, err
 	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
 		fmt.Printf("Error getting deployment %s in %s: %v\n", targetName, namespace, statusError.ErrStatus.Message)
 		return nil, err
 	} else if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
 
 	return deployment, err
 }
 
-func GetStatefulSet(clientset *kubernetes.Clientset, namespace string, targetName string) (*v1.StatefulSet, error) {
-	statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+func GetStatefulSet(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) (*v1.StatefulSet, error) {
+	statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, targetName, metav1.GetOptions{})
 	if errors.IsNotFound(err) {
 		return nil, err
 	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
 		fmt.Printf("Error getting statefulset %s in %s: %v\n", targetName, namespace, statusError.ErrStatus.Message)
 		return nil, err
 	} else if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
 
 	return statefulSet, err
 }
 
-func UpdateDeploymentScale(clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32) (*autoscalingv1.Scale, error) {
-	scale, err := clientset.AppsV1().Deployments(namespace).GetScale(context.TODO(), targetName, metav1.GetOptions{})
+// checkScalePrecondition validates an observed Scale against the caller's
+// preconditions (borrowed from `kubectl scale` semantics). A nil precondition
+// always passes.
+func checkScalePrecondition(scale *autoscalingv1.Scale, kind string, targetName string, namespace string, precondition *ScalePrecondition) error {
+	if precondition == nil {
+		return nil
+	}
+	if precondition.CurrentReplicas >= 0 && scale.Status.Replicas != precondition.CurrentReplicas {
+		return fmt.Errorf("precondition failed for %s %s in %s: expected current replicas %d, found %d", kind, targetName, namespace, precondition.CurrentReplicas, scale.Status.Replicas)
+	}
+	if len(precondition.ResourceVersion) > 0 && scale.ResourceVersion != precondition.ResourceVersion {
+		return fmt.Errorf("precondition failed for %s %s in %s: expected resource version %s, found %s", kind, targetName, namespace, precondition.ResourceVersion, scale.ResourceVersion)
+	}
+	return nil
+}
+
+func UpdateDeploymentScale(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32, precondition *ScalePrecondition) (*autoscalingv1.Scale, error) {
+	scale, err := clientset.AppsV1().Deployments(namespace).GetScale(ctx, targetName, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
+	if err := checkScalePrecondition(scale, "deployment", targetName, namespace, precondition); err != nil {
+		return nil, err
+	}
+	// scale.ResourceVersion is already the version we just observed, so this
+	// UpdateScale call is itself a conflict-safe compare-and-swap.
 	scale.Spec.Replicas = replicas
-	_, err = clientset.AppsV1().Deployments(namespace).UpdateScale(context.TODO(), targetName, scale, metav1.UpdateOptions{})
+	_, err = clientset.AppsV1().Deployments(namespace).UpdateScale(ctx, targetName, scale, metav1.UpdateOptions{})
 	if err != nil {
 		return nil, err
 	}
 	return scale, nil
 }
 
-func UpdateStatefulSetScale(clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32) (*autoscalingv1.Scale, error) {
-	scale, err := clientset.AppsV1().StatefulSets(namespace).GetScale(context.TODO(), targetName, metav1.GetOptions{})
+func UpdateStatefulSetScale(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32, precondition *ScalePrecondition) (*autoscalingv1.Scale, error) {
+	scale, err := clientset.AppsV1().StatefulSets(namespace).GetScale(ctx, targetName, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
+	if err := checkScalePrecondition(scale, "statefulset", targetName, namespace, precondition); err != nil {
+		return nil, err
+	}
+	// scale.ResourceVersion is already the version we just observed, so this
+	// UpdateScale call is itself a conflict-safe compare-and-swap.
 	scale.Spec.Replicas = replicas
-	_, err = clientset.AppsV1().StatefulSets(namespace).UpdateScale(context.TODO(), targetName, scale, metav1.UpdateOptions{})
+	_, err = clientset.AppsV1().StatefulSets(namespace).UpdateScale(ctx, targetName, scale, metav1.UpdateOptions{})
 	if err != nil {
 		return nil, err
 	}
 	return scale, nil
 }
 
-func WaitForDeploymentReplicas(clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32, tries int) error {
-	for i := 0; i < tries; i++ {
-		scale, _ := clientset.AppsV1().Deployments(namespace).GetScale(context.TODO(), targetName, metav1.GetOptions{})
-		if scale.Status.Replicas == replicas {
-			return nil
+// WaitForDeploymentReplicas watches the deployment until its status reports
+// the desired replica count as both present and ready, or ctx is done.
+func WaitForDeploymentReplicas(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32) error {
+	fieldSelector := fmt.Sprintf("metadata.name=%s", targetName)
+	watcher, err := clientset.AppsV1().Deployments(namespace).Watch(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return err
+	}
+
+	condition := func(event watch.Event) (bool, error) {
+		if event.Type == watch.Error {
+			return false, fmt.Errorf("error watching deployment %s in %s: %v", targetName, namespace, event.Object)
+		}
+
+		deployment, ok := event.Object.(*v1.Deployment)
+		if !ok {
+			return false, fmt.Errorf("unexpected object type %T watching deployment %s in %s", event.Object, targetName, namespace)
+		}
+
+		return deployment.Status.Replicas == replicas &&
+			deployment.Status.ReadyReplicas == replicas &&
+			deployment.Status.ObservedGeneration >= deployment.Generation, nil
+	}
+
+	if _, err := watchtools.UntilWithoutRetry(ctx, watcher, condition); err != nil {
+		if err == context.DeadlineExceeded {
+			return fmt.Errorf("deployment %s in %s has not reached %d replicas before the timeout", targetName, namespace, replicas)
 		}
-		time.Sleep(1 * time.Second)
+		return err
 	}
-	return fmt.Errorf("deployment %s in %s has not reached %d replicas after %d tries", targetName, namespace, replicas, tries)
+
+	return nil
 }
 
-func WaitForStatefulSetReplicas(clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32, tries int) error {
-	for i := 0; i < tries; i++ {
-		scale, _ := clientset.AppsV1().StatefulSets(namespace).GetScale(context.TODO(), targetName, metav1.GetOptions{})
-		if scale.Status.Replicas == replicas {
-			return nil
+// WaitForStatefulSetReplicas watches the statefulset until its status reports
+// the desired replica count as both present and ready, or ctx is done.
+func WaitForStatefulSetReplicas(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32) error {
+	fieldSelector := fmt.Sprintf("metadata.name=%s", targetName)
+	watcher, err := clientset.AppsV1().StatefulSets(namespace).Watch(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return err
+	}
+
+	condition := func(event watch.Event) (bool, error) {
+		if event.Type == watch.Error {
+			return false, fmt.Errorf("error watching statefulset %s in %s: %v", targetName, namespace, event.Object)
+		}
+
+		statefulSet, ok := event.Object.(*v1.StatefulSet)
+		if !ok {
+			return false, fmt.Errorf("unexpected object type %T watching statefulset %s in %s", event.Object, targetName, namespace)
+		}
+
+		return statefulSet.Status.Replicas == replicas &&
+			statefulSet.Status.ReadyReplicas == replicas &&
+			statefulSet.Status.ObservedGeneration >= statefulSet.Generation, nil
+	}
+
+	if _, err := watchtools.UntilWithoutRetry(ctx, watcher, condition); err != nil {
+		if err == context.DeadlineExceeded {
+			return fmt.Errorf("statefulset %s in %s has not reached %d replicas before the timeout", targetName, namespace, replicas)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func ScaleReplicaSet(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, precondition *ScalePrecondition, waitMode string) error {
+	replicaSet, err := GetReplicaSet(ctx, clientset, namespace, targetName)
+	if err != nil {
+		return err
+	}
+
+	var originalReplicas = replicaSet.Status.Replicas
+	fmt.Printf("ReplicaSet %s in %s has %d replicas. Scaling to 0...\n", replicaSet.Name, namespace, originalReplicas)
+
+	if _, err := UpdateReplicaSetScale(ctx, clientset, namespace, targetName, 0, precondition); err != nil {
+		return err
+	}
+
+	if waitMode != "none" {
+		if err := WaitForReplicaSetReplicas(ctx, clientset, namespace, targetName, 0); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("ReplicaSet %s in %s now has 0 replicas. Scaling back to %d...\n", replicaSet.Name, namespace, originalReplicas)
+
+	if _, err := UpdateReplicaSetScale(ctx, clientset, namespace, targetName, originalReplicas, nil); err != nil {
+		return err
+	}
+
+	if waitMode != "none" {
+		if err := WaitForReplicaSetReplicas(ctx, clientset, namespace, targetName, originalReplicas); err != nil {
+			return err
+		}
+		if waitMode == "ready" {
+			if err := WaitForReplicaSetReady(ctx, clientset, namespace, targetName); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Printf("ReplicaSet %s in %s has now been scaled back to %d\n", replicaSet.Name, namespace, originalReplicas)
+
+	return nil
+}
+
+func ScaleReplicationController(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, precondition *ScalePrecondition, waitMode string) error {
+	rc, err := GetReplicationController(ctx, clientset, namespace, targetName)
+	if err != nil {
+		return err
+	}
+
+	var originalReplicas = rc.Status.Replicas
+	fmt.Printf("ReplicationController %s in %s has %d replicas. Scaling to 0...\n", rc.Name, namespace, originalReplicas)
+
+	if _, err := UpdateRCScale(ctx, clientset, namespace, targetName, 0, precondition); err != nil {
+		return err
+	}
+
+	if waitMode != "none" {
+		if err := WaitForRCReplicas(ctx, clientset, namespace, targetName, 0); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("ReplicationController %s in %s now has 0 replicas. Scaling back to %d...\n", rc.Name, namespace, originalReplicas)
+
+	if _, err := UpdateRCScale(ctx, clientset, namespace, targetName, originalReplicas, nil); err != nil {
+		return err
+	}
+
+	if waitMode != "none" {
+		if err := WaitForRCReplicas(ctx, clientset, namespace, targetName, originalReplicas); err != nil {
+			return err
+		}
+		if waitMode == "ready" {
+			if err := WaitForRCReady(ctx, clientset, namespace, targetName); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Printf("ReplicationController %s in %s has now been scaled back to %d\n", rc.Name, namespace, originalReplicas)
+
+	return nil
+}
+
+// RestartDeployment forces a new rollout of the deployment's pods without
+// changing the replica count, the same way `kubectl rollout restart` does.
+func RestartDeployment(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, waitMode string) error {
+	deployment, err := GetDeployment(ctx, clientset, namespace, targetName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deployment %s in %s has %d replicas. Restarting...\n", deployment.Name, namespace, deployment.Status.Replicas)
+
+	if err := patchRestartedAt(ctx, clientset, namespace, targetName, "deployments"); err != nil {
+		return err
+	}
+
+	if waitMode != "none" {
+		if err := WaitForDeploymentReplicas(ctx, clientset, namespace, targetName, deployment.Status.Replicas); err != nil {
+			return err
+		}
+		if waitMode == "ready" {
+			if err := WaitForDeploymentReady(ctx, clientset, namespace, targetName); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Printf("Deployment %s in %s has now been restarted\n", deployment.Name, namespace)
+
+	return nil
+}
+
+// RestartStatefulSet forces a new rollout of the statefulset's pods without
+// changing the replica count, the same way `kubectl rollout restart` does.
+func RestartStatefulSet(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, waitMode string) error {
+	statefulSet, err := GetStatefulSet(ctx, clientset, namespace, targetName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("StatefulSet %s in %s has %d replicas. Restarting...\n", statefulSet.Name, namespace, statefulSet.Status.Replicas)
+
+	if err := patchRestartedAt(ctx, clientset, namespace, targetName, "statefulsets"); err != nil {
+		return err
+	}
+
+	if waitMode != "none" {
+		if err := WaitForStatefulSetReplicas(ctx, clientset, namespace, targetName, statefulSet.Status.Replicas); err != nil {
+			return err
+		}
+		if waitMode == "ready" {
+			if err := WaitForStatefulSetReady(ctx, clientset, namespace, targetName); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Printf("StatefulSet %s in %s has now been restarted\n", statefulSet.Name, namespace)
+
+	return nil
+}
+
+// RestartDaemonSet forces a new rollout of the daemonset's pods. DaemonSets
+// have no scale subresource, so this is the only rescale mode available for them.
+func RestartDaemonSet(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, waitMode string) error {
+	daemonSet, err := GetDaemonSet(ctx, clientset, namespace, targetName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("DaemonSet %s in %s has %d desired pods. Restarting...\n", daemonSet.Name, namespace, daemonSet.Status.DesiredNumberScheduled)
+
+	if err := patchRestartedAt(ctx, clientset, namespace, targetName, "daemonsets"); err != nil {
+		return err
+	}
+
+	if waitMode != "none" {
+		if err := WaitForDaemonSetRollout(ctx, clientset, namespace, targetName); err != nil {
+			return err
+		}
+		if waitMode == "ready" {
+			if err := WaitForDaemonSetReady(ctx, clientset, namespace, targetName); err != nil {
+				return err
+			}
 		}
-		time.Sleep(1 * time.Second)
 	}
-	return fmt.Errorf("statefulset %s in %s has not reached %d replicas after %d tries", targetName, namespace, replicas, tries)
+	fmt.Printf("DaemonSet %s in %s has now been restarted\n", daemonSet.Name, namespace)
+
+	return nil
+}
+
+// patchRestartedAt sets the `kubectl.kubernetes.io/restartedAt` annotation on
+// the pod template of the given apps/v1 resource, triggering a new rollout.
+func patchRestartedAt(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, resource string) error {
+	patch := []byte(fmt.Sprintf(
+		`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339),
+	))
+
+	var err error
+	switch resource {
+	case "deployments":
+		_, err = clientset.AppsV1().Deployments(namespace).Patch(ctx, targetName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "statefulsets":
+		_, err = clientset.AppsV1().StatefulSets(namespace).Patch(ctx, targetName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "daemonsets":
+		_, err = clientset.AppsV1().DaemonSets(namespace).Patch(ctx, targetName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("unsupported resource %s for restart", resource)
+	}
+
+	return err
+}
+
+func GetReplicaSet(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) (*v1.ReplicaSet, error) {
+	replicaSet, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, targetName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, err
+	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
+		fmt.Printf("Error getting replicaset %s in %s: %v\n", targetName, namespace, statusError.ErrStatus.Message)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	return replicaSet, err
+}
+
+func GetReplicationController(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) (*corev1.ReplicationController, error) {
+	rc, err := clientset.CoreV1().ReplicationControllers(namespace).Get(ctx, targetName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, err
+	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
+		fmt.Printf("Error getting replicationcontroller %s in %s: %v\n", targetName, namespace, statusError.ErrStatus.Message)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	return rc, err
+}
+
+func GetDaemonSet(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) (*v1.DaemonSet, error) {
+	daemonSet, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, targetName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, err
+	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
+		fmt.Printf("Error getting daemonset %s in %s: %v\n", targetName, namespace, statusError.ErrStatus.Message)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	return daemonSet, err
+}
+
+func UpdateReplicaSetScale(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32, precondition *ScalePrecondition) (*autoscalingv1.Scale, error) {
+	scale, err := clientset.AppsV1().ReplicaSets(namespace).GetScale(ctx, targetName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScalePrecondition(scale, "replicaset", targetName, namespace, precondition); err != nil {
+		return nil, err
+	}
+	// scale.ResourceVersion is already the version we just observed, so this
+	// UpdateScale call is itself a conflict-safe compare-and-swap.
+	scale.Spec.Replicas = replicas
+	_, err = clientset.AppsV1().ReplicaSets(namespace).UpdateScale(ctx, targetName, scale, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+func UpdateRCScale(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32, precondition *ScalePrecondition) (*autoscalingv1.Scale, error) {
+	scale, err := clientset.CoreV1().ReplicationControllers(namespace).GetScale(ctx, targetName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScalePrecondition(scale, "replicationcontroller", targetName, namespace, precondition); err != nil {
+		return nil, err
+	}
+	// scale.ResourceVersion is already the version we just observed, so this
+	// UpdateScale call is itself a conflict-safe compare-and-swap.
+	scale.Spec.Replicas = replicas
+	_, err = clientset.CoreV1().ReplicationControllers(namespace).UpdateScale(ctx, targetName, scale, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// WaitForReplicaSetReplicas watches the replicaset until its status reports
+// the desired replica count as both present and ready, or ctx is done.
+func WaitForReplicaSetReplicas(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32) error {
+	fieldSelector := fmt.Sprintf("metadata.name=%s", targetName)
+	watcher, err := clientset.AppsV1().ReplicaSets(namespace).Watch(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return err
+	}
+
+	condition := func(event watch.Event) (bool, error) {
+		if event.Type == watch.Error {
+			return false, fmt.Errorf("error watching replicaset %s in %s: %v", targetName, namespace, event.Object)
+		}
+
+		replicaSet, ok := event.Object.(*v1.ReplicaSet)
+		if !ok {
+			return false, fmt.Errorf("unexpected object type %T watching replicaset %s in %s", event.Object, targetName, namespace)
+		}
+
+		return replicaSet.Status.Replicas == replicas &&
+			replicaSet.Status.ReadyReplicas == replicas &&
+			replicaSet.Status.ObservedGeneration >= replicaSet.Generation, nil
+	}
+
+	if _, err := watchtools.UntilWithoutRetry(ctx, watcher, condition); err != nil {
+		if err == context.DeadlineExceeded {
+			return fmt.Errorf("replicaset %s in %s has not reached %d replicas before the timeout", targetName, namespace, replicas)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// WaitForRCReplicas watches the replicationcontroller until its status reports
+// the desired replica count as both present and ready, or ctx is done.
+func WaitForRCReplicas(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string, replicas int32) error {
+	fieldSelector := fmt.Sprintf("metadata.name=%s", targetName)
+	watcher, err := clientset.CoreV1().ReplicationControllers(namespace).Watch(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return err
+	}
+
+	condition := func(event watch.Event) (bool, error) {
+		if event.Type == watch.Error {
+			return false, fmt.Errorf("error watching replicationcontroller %s in %s: %v", targetName, namespace, event.Object)
+		}
+
+		rc, ok := event.Object.(*corev1.ReplicationController)
+		if !ok {
+			return false, fmt.Errorf("unexpected object type %T watching replicationcontroller %s in %s", event.Object, targetName, namespace)
+		}
+
+		return rc.Status.Replicas == replicas &&
+			rc.Status.ReadyReplicas == replicas &&
+			rc.Status.ObservedGeneration >= rc.Generation, nil
+	}
+
+	if _, err := watchtools.UntilWithoutRetry(ctx, watcher, condition); err != nil {
+		if err == context.DeadlineExceeded {
+			return fmt.Errorf("replicationcontroller %s in %s has not reached %d replicas before the timeout", targetName, namespace, replicas)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// WaitForDaemonSetRollout watches the daemonset until every desired pod has
+// been updated and is ready, or ctx is done.
+func WaitForDaemonSetRollout(ctx context.Context, clientset *kubernetes.Clientset, namespace string, targetName string) error {
+	fieldSelector := fmt.Sprintf("metadata.name=%s", targetName)
+	watcher, err := clientset.AppsV1().DaemonSets(namespace).Watch(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return err
+	}
+
+	condition := func(event watch.Event) (bool, error) {
+		if event.Type == watch.Error {
+			return false, fmt.Errorf("error watching daemonset %s in %s: %v", targetName, namespace, event.Object)
+		}
+
+		daemonSet, ok := event.Object.(*v1.DaemonSet)
+		if !ok {
+			return false, fmt.Errorf("unexpected object type %T watching daemonset %s in %s", event.Object, targetName, namespace)
+		}
+
+		return daemonSet.Status.ObservedGeneration >= daemonSet.Generation &&
+			daemonSet.Status.UpdatedNumberScheduled == daemonSet.Status.DesiredNumberScheduled &&
+			daemonSet.Status.NumberReady == daemonSet.Status.DesiredNumberScheduled, nil
+	}
+
+	if _, err := watchtools.UntilWithoutRetry(ctx, watcher, condition); err != nil {
+		if err == context.DeadlineExceeded {
+			return fmt.Errorf("daemonset %s in %s has not finished rolling out before the timeout", targetName, namespace)
+		}
+		return err
+	}
+
+	return nil
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"testing"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+)
+
+func TestParseTargetArg(t *testing.T) {
+	cases := []struct {
+		arg  string
+		want targetRef
+	}{
+		{"deployment/nginx", targetRef{kind: "deployment", name: "nginx"}},
+		{"deploy/nginx", targetRef{kind: "deployment", name: "nginx"}},
+		{"statefulset/mysql", targetRef{kind: "statefulset", name: "mysql"}},
+		{"sts/mysql", targetRef{kind: "statefulset", name: "mysql"}},
+		{"replicaset/web", targetRef{kind: "replicaset", name: "web"}},
+		{"rs/web", targetRef{kind: "replicaset", name: "web"}},
+		{"replicationcontroller/web", targetRef{kind: "replicationcontroller", name: "web"}},
+		{"rc/web", targetRef{kind: "replicationcontroller", name: "web"}},
+		{"daemonset/fluentd", targetRef{kind: "daemonset", name: "fluentd"}},
+		{"ds/fluentd", targetRef{kind: "daemonset", name: "fluentd"}},
+		{"nginx", targetRef{kind: "unknown", name: "nginx"}},
+	}
+
+	for _, tc := range cases {
+		if got := parseTargetArg(tc.arg); got != tc.want {
+			t.Errorf("parseTargetArg(%q) = %+v, want %+v", tc.arg, got, tc.want)
+		}
+	}
+}
+
+func TestCheckScalePrecondition(t *testing.T) {
+	scale := &autoscalingv1.Scale{}
+	scale.Status.Replicas = 3
+	scale.ResourceVersion = "12345"
+
+	cases := []struct {
+		name         string
+		precondition *ScalePrecondition
+		wantErr      bool
+	}{
+		{"nil precondition always passes", nil, false},
+		{"matching replicas and version", &ScalePrecondition{CurrentReplicas: 3, ResourceVersion: "12345"}, false},
+		{"unset fields (-1, empty) are not checked", &ScalePrecondition{CurrentReplicas: -1, ResourceVersion: ""}, false},
+		{"replica mismatch", &ScalePrecondition{CurrentReplicas: 5}, true},
+		{"resource version mismatch", &ScalePrecondition{CurrentReplicas: -1, ResourceVersion: "99999"}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkScalePrecondition(scale, "deployment", "nginx", "default", tc.precondition)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkScalePrecondition() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}